@@ -0,0 +1,229 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jerrydevw/zapbuycrypto/internal/signer"
+)
+
+const (
+	binanceBaseURL       = "https://api.binance.com"
+	binanceOrderAPI      = "/api/v3/order"
+	binanceOpenOrdersAPI = "/api/v3/openOrders"
+	binanceAccountAPI    = "/api/v3/account"
+	binanceTickerAPI     = "/api/v3/ticker/price"
+)
+
+// Binance signs requests with an HMAC-SHA256 digest of the query string, as
+// required by /api/v3 endpoints. The digest itself is produced by a
+// signer.Signer, which may or may not hold the secret key in this process.
+type Binance struct {
+	apiKey string
+	signer signer.Signer
+	client *http.Client
+}
+
+func NewBinance(apiKey string, s signer.Signer) *Binance {
+	return &Binance{apiKey: apiKey, signer: s, client: &http.Client{}}
+}
+
+func (b *Binance) do(ctx context.Context, method, path string, query url.Values, signed bool) ([]byte, int, error) {
+	if signed {
+		query.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		signature, err := b.signer.Sign(ctx, query.Encode())
+		if err != nil {
+			return nil, 0, fmt.Errorf("Erro ao assinar requisição: %v", err)
+		}
+		query.Set("signature", signature)
+	}
+
+	var req *http.Request
+	var err error
+	if method == http.MethodPost {
+		req, err = http.NewRequest(method, binanceBaseURL+path, strings.NewReader(query.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequest(method, fmt.Sprintf("%s%s?%s", binanceBaseURL, path, query.Encode()), nil)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("X-MBX-APIKEY", b.apiKey)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := b.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+func (b *Binance) AccountInfo(ctx context.Context) (*AccountInfo, error) {
+	body, status, err := b.do(ctx, http.MethodGet, binanceAccountAPI, url.Values{}, true)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao consultar saldo na Binance: %s", string(body))
+	}
+
+	var raw struct {
+		Balances []struct {
+			Asset string `json:"asset"`
+			Free  string `json:"free"`
+		} `json:"balances"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar resposta do saldo: %v", err)
+	}
+
+	info := &AccountInfo{}
+	for _, balance := range raw.Balances {
+		free, err := strconv.ParseFloat(balance.Free, 64)
+		if err != nil {
+			continue
+		}
+		info.Balances = append(info.Balances, Balance{Asset: balance.Asset, Free: free})
+	}
+	return info, nil
+}
+
+func (b *Binance) PlaceOrder(ctx context.Context, order OrderRequest) (map[string]interface{}, error) {
+	data := url.Values{}
+	data.Set("symbol", order.Symbol)
+	data.Set("side", order.Side)
+	data.Set("type", order.Type)
+
+	if order.QuoteOrderQty > 0 {
+		data.Set("quoteOrderQty", fmt.Sprintf("%.2f", order.QuoteOrderQty))
+	}
+	if order.Quantity > 0 {
+		data.Set("quantity", fmt.Sprintf("%.8f", order.Quantity))
+	}
+	if order.Price > 0 {
+		data.Set("price", fmt.Sprintf("%.2f", order.Price))
+	}
+	if order.StopPrice > 0 {
+		data.Set("stopPrice", fmt.Sprintf("%.2f", order.StopPrice))
+	}
+	if order.Type != TypeMarket {
+		timeInForce := order.TimeInForce
+		if timeInForce == "" {
+			timeInForce = "GTC"
+		}
+		data.Set("timeInForce", timeInForce)
+	}
+
+	body, status, err := b.do(ctx, http.MethodPost, binanceOrderAPI, data, true)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao executar a ordem na Binance: %s", string(body))
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar resposta da ordem: %v", err)
+	}
+	return response, nil
+}
+
+func (b *Binance) ValidatePair(ctx context.Context, pair string) (bool, error) {
+	_, err := b.Ticker(ctx, pair)
+	if err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (b *Binance) Ticker(ctx context.Context, pair string) (float64, error) {
+	query := url.Values{}
+	query.Set("symbol", pair)
+
+	body, status, err := b.do(ctx, http.MethodGet, binanceTickerAPI, query, false)
+	if err != nil {
+		return 0, err
+	}
+	if status != http.StatusOK {
+		return 0, fmt.Errorf("par de moedas %s não suportado na Binance", pair)
+	}
+
+	var raw struct {
+		Price string `json:"price"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return 0, fmt.Errorf("Erro ao decodificar preço: %v", err)
+	}
+	return strconv.ParseFloat(raw.Price, 64)
+}
+
+func (b *Binance) OpenOrders(ctx context.Context, pair string) ([]Order, error) {
+	query := url.Values{}
+	if pair != "" {
+		query.Set("symbol", pair)
+	}
+
+	body, status, err := b.do(ctx, http.MethodGet, binanceOpenOrdersAPI, query, true)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao consultar ordens abertas: %s", string(body))
+	}
+
+	var raw []struct {
+		OrderID int64  `json:"orderId"`
+		Symbol  string `json:"symbol"`
+		Side    string `json:"side"`
+		Price   string `json:"price"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar ordens abertas: %v", err)
+	}
+
+	orders := make([]Order, 0, len(raw))
+	for _, o := range raw {
+		price, _ := strconv.ParseFloat(o.Price, 64)
+		orders = append(orders, Order{
+			OrderID: strconv.FormatInt(o.OrderID, 10),
+			Symbol:  o.Symbol,
+			Side:    o.Side,
+			Price:   price,
+		})
+	}
+	return orders, nil
+}
+
+func (b *Binance) CancelOrder(ctx context.Context, pair, orderID string) error {
+	query := url.Values{}
+	query.Set("symbol", pair)
+	query.Set("orderId", orderID)
+
+	body, status, err := b.do(ctx, http.MethodDelete, binanceOrderAPI, query, true)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("Erro ao cancelar ordem: %s", string(body))
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	coinbaseBaseURL     = "https://api.coinbase.com"
+	coinbaseAccountsAPI = "/api/v3/brokerage/accounts"
+	coinbaseOrdersAPI   = "/api/v3/brokerage/orders"
+	coinbaseProductsAPI = "/api/v3/brokerage/products"
+)
+
+// Coinbase signs requests with a base64-encoded HMAC-SHA256 digest of
+// timestamp + method + requestPath + body, as used by Coinbase Advanced
+// Trade's legacy key-based auth.
+type Coinbase struct {
+	apiKey    string
+	secretKey string
+	client    *http.Client
+}
+
+func NewCoinbase(apiKey, secretKey string) *Coinbase {
+	return &Coinbase{apiKey: apiKey, secretKey: secretKey, client: &http.Client{}}
+}
+
+func (cb *Coinbase) sign(timestamp, method, requestPath, body string) string {
+	h := hmac.New(sha256.New, []byte(cb.secretKey))
+	h.Write([]byte(timestamp + method + requestPath + body))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (cb *Coinbase) do(ctx context.Context, method, requestPath string, body []byte) ([]byte, int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, coinbaseBaseURL+requestPath, reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("CB-ACCESS-KEY", cb.apiKey)
+	req.Header.Set("CB-ACCESS-SIGN", cb.sign(timestamp, method, requestPath, string(body)))
+	req.Header.Set("CB-ACCESS-TIMESTAMP", timestamp)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := cb.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return respBody, resp.StatusCode, nil
+}
+
+func (cb *Coinbase) AccountInfo(ctx context.Context) (*AccountInfo, error) {
+	respBody, status, err := cb.do(ctx, http.MethodGet, coinbaseAccountsAPI, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao consultar saldo na Coinbase: %s", string(respBody))
+	}
+
+	var raw struct {
+		Accounts []struct {
+			Currency         string `json:"currency"`
+			AvailableBalance struct {
+				Value string `json:"value"`
+			} `json:"available_balance"`
+		} `json:"accounts"`
+	}
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar saldo: %v", err)
+	}
+
+	info := &AccountInfo{}
+	for _, account := range raw.Accounts {
+		free, err := strconv.ParseFloat(account.AvailableBalance.Value, 64)
+		if err != nil {
+			continue
+		}
+		info.Balances = append(info.Balances, Balance{Asset: account.Currency, Free: free})
+	}
+	return info, nil
+}
+
+func (cb *Coinbase) PlaceOrder(ctx context.Context, order OrderRequest) (map[string]interface{}, error) {
+	// Coinbase's conditional trigger lives in its own order_configuration
+	// variant (stop_limit_stop_loss_gtc, with stop_price/stop_direction
+	// fields this code doesn't build yet). Until that's wired up, reject
+	// stop orders instead of placing an immediately marketable plain limit
+	// order at the stop price.
+	if order.Type == TypeStopLossLimit {
+		return nil, fmt.Errorf("ordens stop-loss ainda não são suportadas na Coinbase")
+	}
+
+	orderConfig := map[string]interface{}{}
+	switch order.Type {
+	case TypeLimit:
+		orderConfig["limit_limit_gtc"] = map[string]interface{}{
+			"base_size":   fmt.Sprintf("%.8f", order.Quantity),
+			"limit_price": fmt.Sprintf("%.2f", order.Price),
+		}
+	default:
+		orderConfig["market_market_ioc"] = map[string]interface{}{
+			"quote_size": fmt.Sprintf("%.2f", order.QuoteOrderQty),
+		}
+	}
+
+	payload := map[string]interface{}{
+		"client_order_id":     fmt.Sprintf("zapbuycrypto-%d", time.Now().UnixNano()),
+		"product_id":          toCoinbaseProductID(order.Symbol),
+		"side":                order.Side,
+		"order_configuration": orderConfig,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, status, err := cb.do(ctx, http.MethodPost, coinbaseOrdersAPI, body)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao executar a ordem na Coinbase: %s", string(respBody))
+	}
+
+	var raw struct {
+		Success       bool   `json:"success"`
+		OrderID       string `json:"order_id"`
+		FailureReason string `json:"failure_reason"`
+	}
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar resposta da ordem: %v", err)
+	}
+	if !raw.Success {
+		return nil, fmt.Errorf("Erro ao executar a ordem na Coinbase: %s", raw.FailureReason)
+	}
+	return map[string]interface{}{"orderId": raw.OrderID}, nil
+}
+
+func (cb *Coinbase) ValidatePair(ctx context.Context, pair string) (bool, error) {
+	_, status, err := cb.do(ctx, http.MethodGet, coinbaseProductsAPI+"/"+toCoinbaseProductID(pair), nil)
+	if err != nil {
+		return false, err
+	}
+	return status == http.StatusOK, nil
+}
+
+func (cb *Coinbase) Ticker(ctx context.Context, pair string) (float64, error) {
+	respBody, status, err := cb.do(ctx, http.MethodGet, coinbaseProductsAPI+"/"+toCoinbaseProductID(pair), nil)
+	if err != nil {
+		return 0, err
+	}
+	if status != http.StatusOK {
+		return 0, fmt.Errorf("par de moedas %s não suportado na Coinbase", pair)
+	}
+
+	var raw struct {
+		Price string `json:"price"`
+	}
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(raw.Price, 64)
+}
+
+func (cb *Coinbase) OpenOrders(ctx context.Context, pair string) ([]Order, error) {
+	path := coinbaseOrdersAPI + "/historical/batch?order_status=OPEN"
+	if pair != "" {
+		path += "&product_id=" + toCoinbaseProductID(pair)
+	}
+
+	respBody, status, err := cb.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao consultar ordens abertas na Coinbase: %s", string(respBody))
+	}
+
+	var raw struct {
+		Orders []struct {
+			OrderID   string `json:"order_id"`
+			ProductID string `json:"product_id"`
+			Side      string `json:"side"`
+			Price     string `json:"average_filled_price"`
+		} `json:"orders"`
+	}
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return nil, err
+	}
+
+	orders := make([]Order, 0, len(raw.Orders))
+	for _, o := range raw.Orders {
+		price, _ := strconv.ParseFloat(o.Price, 64)
+		orders = append(orders, Order{OrderID: o.OrderID, Symbol: o.ProductID, Side: o.Side, Price: price})
+	}
+	return orders, nil
+}
+
+func (cb *Coinbase) CancelOrder(ctx context.Context, pair, orderID string) error {
+	payload := map[string]interface{}{"order_ids": []string{orderID}}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	respBody, status, err := cb.do(ctx, http.MethodPost, coinbaseOrdersAPI+"/batch_cancel", body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("Erro ao cancelar ordem na Coinbase: %s", string(respBody))
+	}
+	return nil
+}
+
+// toCoinbaseProductID converts a Binance-style "BTCBRL" symbol into
+// Coinbase's dash-separated "BTC-BRL" product ID. It assumes a 3-letter
+// quote currency, which holds for every pair this project trades today.
+func toCoinbaseProductID(symbol string) string {
+	if len(symbol) <= 3 {
+		return strings.ToUpper(symbol)
+	}
+	return symbol[:len(symbol)-3] + "-" + symbol[len(symbol)-3:]
+}
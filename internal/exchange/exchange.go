@@ -0,0 +1,107 @@
+// Package exchange abstracts the crypto venues zapbuycrypto can route orders
+// to, so the WhatsApp handler doesn't need to know whether a given user is
+// trading on Binance, OKX or Coinbase.
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jerrydevw/zapbuycrypto/internal/signer"
+)
+
+// Order types shared across venues. Not every venue supports every type;
+// implementations return an error for combinations they can't place.
+const (
+	TypeMarket        = "MARKET"
+	TypeLimit         = "LIMIT"
+	TypeStopLossLimit = "STOP_LOSS_LIMIT"
+)
+
+// OrderRequest carries the subset of order fields that vary by order type.
+// Zero-value fields are omitted by implementations that don't need them.
+type OrderRequest struct {
+	Symbol        string
+	Side          string
+	Type          string
+	QuoteOrderQty float64
+	Quantity      float64
+	Price         float64
+	StopPrice     float64
+	TimeInForce   string
+}
+
+// Balance is a single asset's available amount in a user's account.
+type Balance struct {
+	Asset string
+	Free  float64
+}
+
+// AccountInfo is a venue-agnostic view of a user's balances.
+type AccountInfo struct {
+	Balances []Balance
+}
+
+// Order is a venue-agnostic view of a resting order, as returned by
+// OpenOrders.
+type Order struct {
+	OrderID string
+	Symbol  string
+	Side    string
+	Price   float64
+}
+
+// Exchange is implemented once per supported venue (Binance, OKX, Coinbase
+// Advanced Trade, ...). Each implementation owns its own request signing
+// scheme; callers only see these venue-agnostic methods.
+type Exchange interface {
+	AccountInfo(ctx context.Context) (*AccountInfo, error)
+	PlaceOrder(ctx context.Context, order OrderRequest) (map[string]interface{}, error)
+	ValidatePair(ctx context.Context, pair string) (bool, error)
+	Ticker(ctx context.Context, pair string) (float64, error)
+	OpenOrders(ctx context.Context, pair string) ([]Order, error)
+	CancelOrder(ctx context.Context, pair, orderID string) error
+}
+
+// Credentials holds whatever an Exchange implementation needs to sign
+// requests. Not every field is used by every venue: Binance and Coinbase
+// only need APIKey/SecretKey, OKX also needs Passphrase. SignerEndpoint is
+// Binance-only: when set, requests are signed by a remote signer.Remote
+// instead of locally from SecretKey.
+type Credentials struct {
+	APIKey         string
+	SecretKey      string
+	Passphrase     string
+	SignerEndpoint string
+}
+
+// New builds the Exchange backend named by `name` (case-insensitive).
+// An empty name defaults to Binance, preserving the behavior this package
+// replaced.
+func New(name string, creds Credentials) (Exchange, error) {
+	switch NormalizeName(name) {
+	case "", "binance":
+		var s signer.Signer
+		if creds.SignerEndpoint != "" {
+			s = signer.NewRemote(creds.SignerEndpoint)
+		} else {
+			s = signer.NewLocal(creds.SecretKey)
+		}
+		return NewBinance(creds.APIKey, s), nil
+	case "okx":
+		return NewOKX(creds.APIKey, creds.SecretKey, creds.Passphrase), nil
+	case "coinbase":
+		return NewCoinbase(creds.APIKey, creds.SecretKey), nil
+	default:
+		return nil, fmt.Errorf("exchange desconhecida: %s", name)
+	}
+}
+
+// NormalizeName trims and lowercases an exchange name so "OKX", " okx " and
+// "okx" all resolve the same way. Callers that branch on a venue name before
+// handing it to New (main's exchangeCredentials, in particular) should run
+// it through here too, so the two switches never disagree.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,287 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	okxBaseURL         = "https://www.okx.com"
+	okxOrderAPI        = "/api/v5/trade/order"
+	okxPendingOrderAPI = "/api/v5/trade/orders-pending"
+	okxCancelOrderAPI  = "/api/v5/trade/cancel-order"
+	okxBalanceAPI      = "/api/v5/account/balance"
+	okxTickerAPI       = "/api/v5/market/ticker"
+	okxInstrumentsAPI  = "/api/v5/public/instruments"
+)
+
+// OKX signs requests with a prehash string of
+// timestamp + method + requestPath + body, HMAC-SHA256'd and base64-encoded,
+// as documented for OKX's v5 API.
+type OKX struct {
+	apiKey     string
+	secretKey  string
+	passphrase string
+	client     *http.Client
+}
+
+func NewOKX(apiKey, secretKey, passphrase string) *OKX {
+	return &OKX{apiKey: apiKey, secretKey: secretKey, passphrase: passphrase, client: &http.Client{}}
+}
+
+func (o *OKX) sign(timestamp, method, requestPath, body string) string {
+	h := hmac.New(sha256.New, []byte(o.secretKey))
+	h.Write([]byte(timestamp + method + requestPath + body))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (o *OKX) do(ctx context.Context, method, requestPath string, body []byte, signed bool) ([]byte, int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, okxBaseURL+requestPath, reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if signed {
+		timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+		req.Header.Set("OK-ACCESS-KEY", o.apiKey)
+		req.Header.Set("OK-ACCESS-SIGN", o.sign(timestamp, method, requestPath, string(body)))
+		req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
+		req.Header.Set("OK-ACCESS-PASSPHRASE", o.passphrase)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := o.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return respBody, resp.StatusCode, nil
+}
+
+func (o *OKX) AccountInfo(ctx context.Context) (*AccountInfo, error) {
+	respBody, status, err := o.do(ctx, http.MethodGet, okxBalanceAPI, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao consultar saldo na OKX: %s", string(respBody))
+	}
+
+	var raw okxEnvelope[[]struct {
+		Details []struct {
+			Ccy      string `json:"ccy"`
+			AvailBal string `json:"availBal"`
+		} `json:"details"`
+	}]
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar saldo: %v", err)
+	}
+
+	info := &AccountInfo{}
+	for _, account := range raw.Data {
+		for _, detail := range account.Details {
+			free, err := strconv.ParseFloat(detail.AvailBal, 64)
+			if err != nil {
+				continue
+			}
+			info.Balances = append(info.Balances, Balance{Asset: detail.Ccy, Free: free})
+		}
+	}
+	return info, nil
+}
+
+func (o *OKX) PlaceOrder(ctx context.Context, order OrderRequest) (map[string]interface{}, error) {
+	// Conditional orders go through /api/v5/trade/order-algo, a different
+	// endpoint and response shape from the plain order API below. Until
+	// that's wired up, reject stop orders instead of placing an immediately
+	// marketable plain limit order at the stop price.
+	if order.Type == TypeStopLossLimit {
+		return nil, fmt.Errorf("ordens stop-loss ainda não são suportadas na OKX")
+	}
+
+	ordType := "market"
+	if order.Type == TypeLimit {
+		ordType = "limit"
+	}
+
+	payload := map[string]interface{}{
+		"instId":  toOKXInstId(order.Symbol),
+		"tdMode":  "cash",
+		"side":    lower(order.Side),
+		"ordType": ordType,
+	}
+	if order.Quantity > 0 {
+		payload["sz"] = fmt.Sprintf("%.8f", order.Quantity)
+	} else if order.QuoteOrderQty > 0 {
+		// Spot market orders may be sized in the quote currency instead.
+		payload["sz"] = fmt.Sprintf("%.2f", order.QuoteOrderQty)
+		payload["tgtCcy"] = "quote_ccy"
+	}
+	if order.Price > 0 {
+		payload["px"] = fmt.Sprintf("%.2f", order.Price)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, status, err := o.do(ctx, http.MethodPost, okxOrderAPI, body, true)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao executar a ordem na OKX: %s", string(respBody))
+	}
+
+	var raw okxEnvelope[[]struct {
+		OrdID string `json:"ordId"`
+	}]
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar resposta da ordem: %v", err)
+	}
+	if len(raw.Data) == 0 {
+		return nil, fmt.Errorf("Erro ao executar a ordem na OKX: %s", raw.Msg)
+	}
+	return map[string]interface{}{"orderId": raw.Data[0].OrdID}, nil
+}
+
+func (o *OKX) ValidatePair(ctx context.Context, pair string) (bool, error) {
+	respBody, status, err := o.do(ctx, http.MethodGet, okxInstrumentsAPI+"?instType=SPOT&instId="+toOKXInstId(pair), nil, false)
+	if err != nil {
+		return false, err
+	}
+	if status != http.StatusOK {
+		return false, nil
+	}
+
+	var raw okxEnvelope[[]struct {
+		InstId string `json:"instId"`
+	}]
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return false, err
+	}
+	return len(raw.Data) > 0, nil
+}
+
+func (o *OKX) Ticker(ctx context.Context, pair string) (float64, error) {
+	respBody, status, err := o.do(ctx, http.MethodGet, okxTickerAPI+"?instId="+toOKXInstId(pair), nil, false)
+	if err != nil {
+		return 0, err
+	}
+	if status != http.StatusOK {
+		return 0, fmt.Errorf("par de moedas %s não suportado na OKX", pair)
+	}
+
+	var raw okxEnvelope[[]struct {
+		Last string `json:"last"`
+	}]
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return 0, err
+	}
+	if len(raw.Data) == 0 {
+		return 0, fmt.Errorf("par de moedas %s não suportado na OKX", pair)
+	}
+	return strconv.ParseFloat(raw.Data[0].Last, 64)
+}
+
+func (o *OKX) OpenOrders(ctx context.Context, pair string) ([]Order, error) {
+	path := okxPendingOrderAPI
+	if pair != "" {
+		path += "?instId=" + toOKXInstId(pair)
+	}
+
+	respBody, status, err := o.do(ctx, http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Erro ao consultar ordens abertas na OKX: %s", string(respBody))
+	}
+
+	var raw okxEnvelope[[]struct {
+		OrdID  string `json:"ordId"`
+		InstId string `json:"instId"`
+		Side   string `json:"side"`
+		Px     string `json:"px"`
+	}]
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return nil, err
+	}
+
+	orders := make([]Order, 0, len(raw.Data))
+	for _, ord := range raw.Data {
+		price, _ := strconv.ParseFloat(ord.Px, 64)
+		orders = append(orders, Order{OrderID: ord.OrdID, Symbol: ord.InstId, Side: ord.Side, Price: price})
+	}
+	return orders, nil
+}
+
+func (o *OKX) CancelOrder(ctx context.Context, pair, orderID string) error {
+	payload := map[string]interface{}{
+		"instId": toOKXInstId(pair),
+		"ordId":  orderID,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	respBody, status, err := o.do(ctx, http.MethodPost, okxCancelOrderAPI, body, true)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("Erro ao cancelar ordem na OKX: %s", string(respBody))
+	}
+	return nil
+}
+
+// okxEnvelope wraps OKX's common {code, msg, data} response shape.
+type okxEnvelope[T any] struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+// toOKXInstId converts a Binance-style "BTCBRL" symbol into OKX's
+// dash-separated "BTC-BRL" instrument ID. It assumes a 3-letter quote
+// currency, which holds for every pair this project trades today.
+func toOKXInstId(symbol string) string {
+	if len(symbol) <= 3 {
+		return symbol
+	}
+	return symbol[:len(symbol)-3] + "-" + symbol[len(symbol)-3:]
+}
+
+func lower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
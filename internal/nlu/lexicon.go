@@ -0,0 +1,53 @@
+package nlu
+
+// intentKeywords maps each intent to the trigger words recognized across
+// Portuguese, English and Spanish phrasings. Fuzzy matching (see
+// matchIntent and similarity) catches typos like "compar" that aren't
+// listed verbatim.
+var intentKeywords = map[string][]string{
+	Buy: {
+		"comprar", "compre", "compra", "comprando",
+		"buy", "buying",
+	},
+	Balance: {
+		"saldo", "saldos", "balanco",
+		"balance", "balances",
+	},
+	Price: {
+		"preco", "precos", "cotacao", "cotacoes",
+		"price", "prices",
+		"precio", "precios",
+	},
+	Help: {
+		"ajuda", "help", "ayuda",
+	},
+	Cancel: {
+		"cancelar", "cancela",
+		"cancel",
+	},
+}
+
+// currencyWords maps currency names and symbols, in all three supported
+// languages, to the quote_currency slot value.
+var currencyWords = map[string]string{
+	"r$": "BRL", "brl": "BRL", "real": "BRL", "reais": "BRL",
+	"us$": "USD", "usd": "USD", "dollar": "USD", "dollars": "USD", "dolar": "USD", "dolares": "USD",
+	"eur": "EUR", "euro": "EUR", "euros": "EUR",
+}
+
+// knownAssets lists the crypto tickers this project trades; anything else is
+// still captured as the asset slot (ValidatePair rejects unsupported pairs
+// downstream), but these get matched even when typed in lowercase prose.
+var knownAssets = map[string]bool{
+	"btc": true, "eth": true, "usdt": true, "bnb": true,
+	"sol": true, "ada": true, "xrp": true, "doge": true,
+}
+
+// assetStopWords lists tokens extractSlots's asset fallback must never treat
+// as a ticker: prepositions glued into commands across pt/en/es, the "via
+// <exchange>" override, and the exchange names it can name.
+var assetStopWords = map[string]bool{
+	"em": true, "de": true, "do": true, "da": true, "a": true, "para": true,
+	"en": true, "of": true, "in": true, "to": true,
+	"via": true, "binance": true, "okx": true, "coinbase": true,
+}
@@ -0,0 +1,234 @@
+// Package nlu turns a free-form WhatsApp message into a structured Intent,
+// replacing brittle strings.HasPrefix/strings.Fields parsing with tokenize →
+// normalize → match. It supports Portuguese, English and Spanish phrasings
+// and tolerates typos via a Levenshtein fallback.
+package nlu
+
+import "strings"
+
+// Intent names Parse can return. Unknown means no intent matched with
+// sufficient confidence.
+const (
+	Buy     = "buy"
+	Balance = "balance"
+	Price   = "price"
+	Help    = "help"
+	Cancel  = "cancel"
+	Unknown = "unknown"
+)
+
+// minConfidence is the lowest fuzzy-match score Parse accepts before giving
+// up and returning Unknown.
+const minConfidence = 0.6
+
+// Intent is the structured result of parsing a message: which action the
+// user wants, the slots extracted from it, and how confident the match is.
+type Intent struct {
+	Name       string
+	Slots      map[string]string
+	Confidence float64
+}
+
+// Parse tokenizes and normalizes message, matches it against the known
+// intents (with fuzzy fallback for typos), and extracts whatever amount,
+// asset and quote_currency slots are present.
+func Parse(message string) Intent {
+	tokens := tokenize(message)
+
+	name, confidence := matchIntent(tokens)
+	slots := extractSlots(tokens)
+
+	if name == "" {
+		return Intent{Name: Unknown, Slots: slots, Confidence: 0}
+	}
+	return Intent{Name: name, Slots: slots, Confidence: confidence}
+}
+
+// tokenize lower-cases, strips accents and currency symbols glued to
+// numbers (e.g. "R$100" -> "r$", "100"), and splits on whitespace.
+func tokenize(message string) []string {
+	normalized := stripAccents(strings.ToLower(strings.TrimSpace(message)))
+	normalized = strings.ReplaceAll(normalized, "r$", "r$ ")
+	normalized = strings.ReplaceAll(normalized, "us$", "us$ ")
+	normalized = strings.ReplaceAll(normalized, ",", ".")
+
+	fields := strings.Fields(normalized)
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		tokens = append(tokens, strings.Trim(field, ".!?"))
+	}
+	return tokens
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "ã", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "õ", "o", "ô", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c",
+)
+
+func stripAccents(s string) string {
+	return accentReplacer.Replace(s)
+}
+
+// matchIntent finds the intent whose keyword list best matches the tokens,
+// first by exact membership, then by Levenshtein distance for typos.
+func matchIntent(tokens []string) (string, float64) {
+	bestName := ""
+	bestScore := 0.0
+
+	for _, token := range tokens {
+		for intent, keywords := range intentKeywords {
+			for _, keyword := range keywords {
+				score := similarity(token, keyword)
+				if score > bestScore {
+					bestScore = score
+					bestName = intent
+				}
+			}
+		}
+	}
+
+	if bestScore < minConfidence {
+		return "", 0
+	}
+	return bestName, bestScore
+}
+
+// similarity scores two words from 0 (nothing alike) to 1 (identical),
+// based on Levenshtein edit distance relative to the longer word's length.
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	dist := levenshtein(a, b)
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(dist)/float64(longest)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows, cols := len(ra)+1, len(rb)+1
+
+	dist := make([][]int, rows)
+	for i := range dist {
+		dist[i] = make([]int, cols)
+		dist[i][0] = i
+	}
+	for j := 0; j < cols; j++ {
+		dist[0][j] = j
+	}
+
+	for i := 1; i < rows; i++ {
+		for j := 1; j < cols; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			dist[i][j] = min3(
+				dist[i-1][j]+1,
+				dist[i][j-1]+1,
+				dist[i-1][j-1]+cost,
+			)
+		}
+	}
+	return dist[rows-1][cols-1]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// extractSlots pulls amount, asset and quote_currency out of the tokenized
+// message. Any token that parses as a number becomes amount; any token
+// matching a currency word or symbol becomes quote_currency; any token
+// matching a known crypto ticker becomes asset. If no token matches a known
+// ticker, the first remaining token that isn't a number, currency, command
+// keyword or connector word is taken as the asset instead, so tickers this
+// project hasn't special-cased yet (e.g. "shib") still get through -
+// ValidatePair rejects whatever the exchange doesn't actually support.
+func extractSlots(tokens []string) map[string]string {
+	slots := map[string]string{}
+
+	for _, token := range tokens {
+		if amount, ok := parseAmount(token); ok {
+			slots["amount"] = amount
+			continue
+		}
+		if currency, ok := currencyWords[token]; ok {
+			slots["quote_currency"] = currency
+			continue
+		}
+		if knownAssets[token] {
+			slots["asset"] = strings.ToUpper(token)
+			continue
+		}
+		if _, ok := slots["asset"]; !ok && isAssetCandidate(token) {
+			slots["asset"] = strings.ToUpper(token)
+		}
+	}
+	return slots
+}
+
+// isAssetCandidate reports whether token could plausibly be an unlisted
+// crypto ticker: not a connector word, not one of the intent keywords that
+// triggered this parse in the first place, and long enough to be a ticker
+// rather than noise.
+func isAssetCandidate(token string) bool {
+	if len(token) < 2 || assetStopWords[token] {
+		return false
+	}
+	return !isIntentKeyword(token)
+}
+
+func isIntentKeyword(token string) bool {
+	for _, keywords := range intentKeywords {
+		for _, keyword := range keywords {
+			if token == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseAmount recognizes a token as a numeric amount, stripping a leading
+// currency symbol if one is glued on (e.g. "r$100").
+func parseAmount(token string) (string, bool) {
+	trimmed := strings.TrimPrefix(token, "r$")
+	trimmed = strings.TrimPrefix(trimmed, "us$")
+	trimmed = strings.TrimPrefix(trimmed, "$")
+	if trimmed == "" {
+		return "", false
+	}
+
+	hasDigit := false
+	for _, r := range trimmed {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r == '.':
+		default:
+			return "", false
+		}
+	}
+	if !hasDigit {
+		return "", false
+	}
+	return trimmed, true
+}
@@ -0,0 +1,68 @@
+package nlu
+
+import "testing"
+
+func TestParseIntents(t *testing.T) {
+	cases := []struct {
+		message string
+		want    string
+	}{
+		{"comprar 100 reais de btc", Buy},
+		{"buy 50 usd of eth", Buy},
+		{"qual o saldo?", Balance},
+		{"cotacao do btc", Price},
+		{"ajuda", Help},
+		{"cancelar", Cancel},
+		{"compar 100 reais de btc", Buy}, // typo, caught by fuzzy match
+		{"oi tudo bem", Unknown},
+	}
+
+	for _, tc := range cases {
+		got := Parse(tc.message)
+		if got.Name != tc.want {
+			t.Errorf("Parse(%q).Name = %q, want %q", tc.message, got.Name, tc.want)
+		}
+	}
+}
+
+func TestExtractSlotsKnownAsset(t *testing.T) {
+	slots := extractSlots(tokenize("comprar 100 reais de btc"))
+
+	if slots["amount"] != "100" {
+		t.Errorf("amount = %q, want %q", slots["amount"], "100")
+	}
+	if slots["quote_currency"] != "BRL" {
+		t.Errorf("quote_currency = %q, want %q", slots["quote_currency"], "BRL")
+	}
+	if slots["asset"] != "BTC" {
+		t.Errorf("asset = %q, want %q", slots["asset"], "BTC")
+	}
+}
+
+func TestExtractSlotsUnlistedAsset(t *testing.T) {
+	// "shib" isn't in knownAssets, so it must come through the fallback arm.
+	slots := extractSlots(tokenize("comprar 100 reais de shib"))
+
+	if slots["asset"] != "SHIB" {
+		t.Errorf("asset = %q, want %q (fallback for unlisted ticker)", slots["asset"], "SHIB")
+	}
+}
+
+func TestExtractSlotsFallbackIgnoresConnectorsAndKeywords(t *testing.T) {
+	// None of "comprar", "de", "via", "binance" should ever be mistaken for
+	// the asset - only "pepe" should survive the stop-word/keyword filter.
+	slots := extractSlots(tokenize("comprar 100 reais de pepe via binance"))
+
+	if slots["asset"] != "PEPE" {
+		t.Errorf("asset = %q, want %q", slots["asset"], "PEPE")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := similarity("cancelar", "cancelar"); got != 1 {
+		t.Errorf("similarity of identical words = %v, want 1", got)
+	}
+	if got := similarity("cancelar", "cancela"); got < minConfidence {
+		t.Errorf("similarity(%q, %q) = %v, want >= %v", "cancelar", "cancela", got, minConfidence)
+	}
+}
@@ -0,0 +1,39 @@
+package secretstore
+
+import (
+	"context"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// WatchRotations subscribes to subscriptionID and invalidates the cache
+// entry for whatever secret a SECRET_VERSION_ADD notification names, so
+// rotation takes effect immediately instead of waiting out the TTL. Secret
+// Manager delivers eventType and secretId as message attributes, with an
+// empty Data payload, and secretId as the full resource path
+// (projects/P/secrets/NAME) rather than the bare name Get/Invalidate are
+// keyed by. It blocks until ctx is canceled; run it in its own goroutine.
+func (s *Store) WatchRotations(ctx context.Context, client *pubsub.Client, subscriptionID string) error {
+	sub := client.Subscription(subscriptionID)
+
+	return sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		defer msg.Ack()
+
+		if msg.Attributes["eventType"] == "SECRET_VERSION_ADD" {
+			s.Invalidate(secretNameFromResourcePath(msg.Attributes["secretId"]))
+		}
+	})
+}
+
+// secretNameFromResourcePath extracts NAME from a Secret Manager resource
+// path like "projects/P/secrets/NAME" or "projects/P/secrets/NAME/versions/V".
+func secretNameFromResourcePath(resourcePath string) string {
+	parts := strings.Split(resourcePath, "/")
+	for i, part := range parts {
+		if part == "secrets" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return resourcePath
+}
@@ -0,0 +1,20 @@
+package secretstore
+
+import "testing"
+
+func TestSecretNameFromResourcePath(t *testing.T) {
+	cases := []struct {
+		resourcePath string
+		want         string
+	}{
+		{"projects/my-project/secrets/BINANCE_API_KEY", "BINANCE_API_KEY"},
+		{"projects/my-project/secrets/BINANCE_API_KEY/versions/3", "BINANCE_API_KEY"},
+		{"BINANCE_API_KEY", "BINANCE_API_KEY"}, // already bare, passed through
+	}
+
+	for _, tc := range cases {
+		if got := secretNameFromResourcePath(tc.resourcePath); got != tc.want {
+			t.Errorf("secretNameFromResourcePath(%q) = %q, want %q", tc.resourcePath, got, tc.want)
+		}
+	}
+}
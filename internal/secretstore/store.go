@@ -0,0 +1,118 @@
+// Package secretstore wraps Secret Manager with a small TTL cache, so the
+// WhatsApp webhook isn't paying for a fresh client and an API round-trip on
+// every incoming message. A single Store is built once in main and shared
+// across requests.
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+)
+
+// defaultTTL is how long a cached secret is served without re-checking
+// Secret Manager, absent a rotation event invalidating it sooner.
+const defaultTTL = 5 * time.Minute
+
+// Secret is a decoded Secret Manager entry: the raw JSON payload keyed by
+// field name (API keys, tokens, and similar credentials).
+type Secret struct {
+	Name  string
+	Value map[string]interface{}
+}
+
+type cacheEntry struct {
+	secret    *Secret
+	expiresAt time.Time
+}
+
+// Store owns a single long-lived secretmanager.Client and caches decoded
+// secrets by name for ttl. Invalidate drops an entry immediately, which
+// WatchRotations wires up to Secret Manager's Pub/Sub rotation
+// notifications.
+type Store struct {
+	client    *secretmanager.Client
+	projectID string
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+// NewStore creates a Store backed by a fresh secretmanager.Client, which is
+// reused across all Get calls for the Store's lifetime. Call Close when
+// done with it.
+func NewStore(ctx context.Context, projectID string) (*Store, error) {
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar client do Secret Manager: %v", err)
+	}
+	return &Store{
+		client:    client,
+		projectID: projectID,
+		ttl:       defaultTTL,
+		cache:     make(map[string]*cacheEntry),
+	}, nil
+}
+
+// Close releases the underlying Secret Manager client.
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+// Get returns the named secret, serving from cache when the entry hasn't
+// expired and hitting Secret Manager otherwise.
+func (s *Store) Get(ctx context.Context, name string) (*Secret, error) {
+	s.mu.Lock()
+	if entry, ok := s.cache[name]; ok && time.Now().Before(entry.expiresAt) {
+		s.mu.Unlock()
+		return entry.secret, nil
+	}
+	s.mu.Unlock()
+
+	secret, err := s.fetch(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[name] = &cacheEntry{secret: secret, expiresAt: time.Now().Add(s.ttl)}
+	s.mu.Unlock()
+
+	return secret, nil
+}
+
+// Invalidate drops name from the cache, so the next Get fetches the latest
+// version instead of serving a stale one.
+func (s *Store) Invalidate(name string) {
+	s.mu.Lock()
+	delete(s.cache, name)
+	s.mu.Unlock()
+}
+
+func (s *Store) fetch(ctx context.Context, name string) (*Secret, error) {
+	accessRequest := &secretmanagerpb.AccessSecretVersionRequest{
+		Name: s.buildSecretPath(name),
+	}
+
+	result, err := s.client.AccessSecretVersion(ctx, accessRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var secretData map[string]interface{}
+	if err := json.Unmarshal(result.Payload.Data, &secretData); err != nil {
+		return nil, err
+	}
+
+	return &Secret{Name: name, Value: secretData}, nil
+}
+
+func (s *Store) buildSecretPath(name string) string {
+	return fmt.Sprintf("projects/%s/secrets/%s/versions/latest", s.projectID, name)
+}
@@ -0,0 +1,24 @@
+package signer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Local signs with a secret key held directly in process memory. This is
+// the behavior every user had before remote signing existed.
+type Local struct {
+	secretKey string
+}
+
+func NewLocal(secretKey string) *Local {
+	return &Local{secretKey: secretKey}
+}
+
+func (l *Local) Sign(ctx context.Context, payload string) (string, error) {
+	h := hmac.New(sha256.New, []byte(l.secretKey))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const identityTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/identity"
+
+// Remote delegates signing to an external enclave over JSON-RPC, so the
+// Binance secret key never enters this process's memory — only the
+// resulting signature does. Requests are authenticated with a short-lived
+// GCP identity token scoped to the signer's own URL as audience, the same
+// pattern used to call other authenticated Cloud Run services.
+type Remote struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewRemote(endpoint string) *Remote {
+	return &Remote{endpoint: endpoint, client: &http.Client{}}
+}
+
+func (r *Remote) Sign(ctx context.Context, payload string) (string, error) {
+	token, err := fetchIdentityToken(ctx, r.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("Erro ao obter token de identidade: %v", err)
+	}
+
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "sign",
+		"params":  map[string]string{"payload": payload},
+		"id":      1,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Erro ao assinar remotamente: %s", string(body))
+	}
+
+	var rpcResp struct {
+		Result struct {
+			Signature string `json:"signature"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return "", fmt.Errorf("Erro ao decodificar resposta de assinatura: %v", err)
+	}
+	if rpcResp.Error != nil {
+		return "", fmt.Errorf("Erro ao assinar remotamente: %s", rpcResp.Error.Message)
+	}
+	return rpcResp.Result.Signature, nil
+}
+
+// fetchIdentityToken asks the GCE metadata server for a short-lived identity
+// token scoped to `audience`, following Google's documented pattern for
+// service-to-service authentication on Cloud Run.
+func fetchIdentityToken(ctx context.Context, audience string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, identityTokenURL+"?audience="+audience+"&format=full", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	client := &http.Client{}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server retornou %d: %s", resp.StatusCode, string(body))
+	}
+	return string(body), nil
+}
@@ -0,0 +1,12 @@
+// Package signer abstracts how Binance's query-string signatures get
+// produced, so the raw API secret doesn't have to live in this process's
+// memory on every webhook request.
+package signer
+
+import "context"
+
+// Signer produces the HMAC-SHA256 hex digest Binance's /api/v3 endpoints
+// expect over a request's query string.
+type Signer interface {
+	Sign(ctx context.Context, payload string) (string, error)
+}
@@ -3,39 +3,45 @@ package main
 import (
 	"bytes"
 	"cloud.google.com/go/logging"
-	secretmanager "cloud.google.com/go/secretmanager/apiv1"
-	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"cloud.google.com/go/pubsub"
 	"context"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/jerrydevw/zapbuycrypto/internal/exchange"
+	"github.com/jerrydevw/zapbuycrypto/internal/nlu"
+	"github.com/jerrydevw/zapbuycrypto/internal/secretstore"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	baseURL    = "https://api.binance.com"
-	orderAPI   = "/api/v3/order"
-	accountAPI = "/api/v3/account"
-	BRL        = "BRL"
+	BRL = "BRL"
+
+	defaultExchange = "binance"
+	gcpProjectID    = "425135792660"
 )
 
 var (
-	binanceApiKey    = ""
-	binanceSecretKey = ""
-	whatsappToken    = ""
-	whatsappApiUrl   = ""
-	whatsappPhoneId  = ""
-	logger           *logging.Logger
+	whatsappToken     = ""
+	whatsappApiUrl    = ""
+	whatsappPhoneId   = ""
+	whatsappAppSecret = ""
+	logger            *logging.Logger
+	secretStore       *secretstore.Store
+
+	scheduler   = newDCAScheduler()
+	rateLimiter = newRateLimiter(5, 1.0/60.0) // 5 mensagens de rajada, 1 a cada 60s em regime
 )
 
 func handlePanic() {
@@ -48,7 +54,24 @@ func handlePanic() {
 
 func main() {
 	defer handlePanic()
-	secret, err := accessSecretVersion("whatsappConfigs")
+
+	ctx := context.Background()
+	store, err := secretstore.NewStore(ctx, gcpProjectID)
+	if err != nil {
+		log.Fatalf("Falha ao iniciar o Secret Store: %v", err)
+	}
+	secretStore = store
+	defer secretStore.Close()
+
+	loggingClient, err := logging.NewClient(ctx, fmt.Sprintf("projects/%s", gcpProjectID))
+	if err != nil {
+		log.Printf("Erro ao iniciar o Cloud Logging: %v", err)
+	} else {
+		logger = loggingClient.Logger("zapbuycrypto")
+		defer loggingClient.Close()
+	}
+
+	secret, err := secretStore.Get(ctx, "whatsappConfigs")
 	if err != nil {
 		log.Fatalf("Falha ao acessar o segredo: %v", err)
 	}
@@ -56,11 +79,17 @@ func main() {
 		whatsappPhoneId = secret.Value["WHATSAPP_PHONE_ID"].(string)
 		whatsappToken = secret.Value["WHATSAPP_TOKEN"].(string)
 		whatsappApiUrl = secret.Value["WHATSAPP_API_URL"].(string)
+		whatsappAppSecret = secret.Value["WHATSAPP_APP_SECRET"].(string)
+
+		if subscriptionID, ok := secret.Value["SECRET_ROTATION_SUBSCRIPTION"].(string); ok && subscriptionID != "" {
+			startRotationWatcher(ctx, subscriptionID)
+		}
 	}
 
 	r := gin.Default()
+	r.Use(requestLogger())
 
-	r.POST("/whatsapp/webhook", handleWhatsAppWebhook)
+	r.POST("/whatsapp/webhook", verifyWhatsAppSignature(), handleWhatsAppWebhook)
 	r.GET("/whatsapp/webhook", verifyWebhook)
 
 	r.GET("/health-check", healthCheck)
@@ -71,118 +100,172 @@ func main() {
 	}
 }
 
-func healthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"status": 200})
-}
-
-func getAccountInfo() (*AccountInfo, error) {
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-	queryString := "timestamp=" + timestamp
-	signature := createSignature(binanceSecretKey, queryString)
-	fullURL := fmt.Sprintf("%s%s?%s&signature=%s", baseURL, accountAPI, queryString, signature)
-
-	req, err := http.NewRequest("GET", fullURL, nil)
+// startRotationWatcher subscribes secretStore to Secret Manager's rotation
+// notifications in the background, so a rotated secret is evicted from the
+// cache immediately instead of being served stale for up to its TTL. It's
+// opt-in: absent a SECRET_ROTATION_SUBSCRIPTION in whatsappConfigs, the
+// cache falls back to expiring on TTL alone.
+func startRotationWatcher(ctx context.Context, subscriptionID string) {
+	pubsubClient, err := pubsub.NewClient(ctx, gcpProjectID)
 	if err != nil {
-		return nil, err
+		log.Printf("Erro ao iniciar client do Pub/Sub: %v", err)
+		return
 	}
-	req.Header.Set("X-MBX-APIKEY", binanceApiKey)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
 
-	client := &http.Client{}
-	resp, err := client.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	go func() {
+		if err := secretStore.WatchRotations(ctx, pubsubClient, subscriptionID); err != nil {
+			log.Printf("Erro ao observar rotação de segredos: %v", err)
+		}
+	}()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// requestLogger gives every request a correlation ID - reusing Google's
+// X-Cloud-Trace-Context if the load balancer already set one, otherwise
+// generating one - and emits a structured entry to Cloud Logging once the
+// handler finishes, passing requestID straight to logRequest.
+func requestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader("X-Cloud-Trace-Context")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Erro ao consultar saldo: %s", string(body))
-	}
+		c.Next()
 
-	var accountInfo AccountInfo
-	if err := json.Unmarshal(body, &accountInfo); err != nil {
-		return nil, fmt.Errorf("Erro ao decodificar resposta do saldo: %v", err)
+		logRequest(c, requestID, start)
 	}
-
-	return &accountInfo, nil
 }
 
-func buyCrypto(symbol string, fiatAmount float64) map[string]interface{} {
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-
-	data := url.Values{}
-	data.Set("symbol", symbol)
-	data.Set("side", "BUY")
-	data.Set("type", "MARKET")
-	data.Set("quoteOrderQty", fmt.Sprintf("%.2f", fiatAmount))
-	data.Set("timestamp", timestamp)
-
-	signature := createSignature(binanceSecretKey, data.Encode())
-	data.Set("signature", signature)
-
-	req, err := http.NewRequest("POST", baseURL+orderAPI, strings.NewReader(data.Encode()))
-	if err != nil {
-		fmt.Println("Erro ao criar requisição de compra:", err)
-		return nil
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
 	}
-	req.Header.Set("X-MBX-APIKEY", binanceApiKey)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return hex.EncodeToString(buf)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Println("Erro ao executar a compra:", err)
-		return nil
+// logRequest emits one structured entry per request to Cloud Logging,
+// tagging it with the correlation ID, the sender's phone number (hashed,
+// never logged in the clear) and the intent handleWhatsAppWebhook resolved,
+// if any. It's a no-op until main successfully wires up logger.
+func logRequest(c *gin.Context, requestID string, start time.Time) {
+	if logger == nil {
+		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Erro ao ler resposta da compra:", err)
-		return nil
-	}
+	status := c.Writer.Status()
+	severity := logging.Info
+	switch {
+	case status >= http.StatusInternalServerError:
+		severity = logging.Error
+	case status >= http.StatusBadRequest:
+		severity = logging.Warning
+	}
+
+	labels := map[string]string{}
+	if from, ok := c.Get("from"); ok {
+		labels["from"] = hashPhoneNumber(from.(string))
+	}
+	if intent, ok := c.Get("intent"); ok {
+		labels["intent"] = intent.(string)
+	}
+
+	logger.Log(logging.Entry{
+		Timestamp: start,
+		Severity:  severity,
+		Payload:   fmt.Sprintf("%s %s -> %d", c.Request.Method, c.Request.URL.Path, status),
+		Trace:     requestID,
+		Labels:    labels,
+		HTTPRequest: &logging.HTTPRequest{
+			Request: c.Request,
+			Status:  status,
+			Latency: time.Since(start),
+		},
+	})
+}
 
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Erro ao executar a compra: %s\n", string(body))
-		return nil
-	}
+// hashPhoneNumber redacts a phone number before it reaches logs or labels,
+// salting with whatsappAppSecret so the digest can't be reversed but stays
+// stable enough to correlate a user's messages across log entries.
+func hashPhoneNumber(phone string) string {
+	return hmacHex(whatsappAppSecret, []byte(phone))
+}
 
-	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		fmt.Println("Erro ao decodificar resposta da compra:", err)
-		return nil
-	}
-	return response
+func healthCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": 200})
 }
 
-func hasSufficientBalance(accountInfo *AccountInfo, asset string, requiredAmount float64) bool {
+func hasSufficientBalance(accountInfo *exchange.AccountInfo, asset string, requiredAmount float64) bool {
 	for _, balance := range accountInfo.Balances {
 		if balance.Asset == asset {
-			free, err := strconv.ParseFloat(balance.Free, 64)
-			return err == nil && free >= requiredAmount
+			return balance.Free >= requiredAmount
 		}
 	}
 	return false
 }
 
-func createSignature(secretKey, data string) string {
-	h := hmac.New(sha256.New, []byte(secretKey))
-	h.Write([]byte(data))
-	return hex.EncodeToString(h.Sum(nil))
+// exchangeCredentials picks out the Secret Manager fields the named backend
+// needs, keyed by the name resolveExchange settled on (a "via" override, else
+// the user's EXCHANGE field); any name other than okx or coinbase gets
+// Binance's fields.
+func exchangeCredentials(secrets *secretstore.Secret, name string) exchange.Credentials {
+	switch name {
+	case "okx":
+		return exchange.Credentials{
+			APIKey:     secrets.Value["OKX_API_KEY"].(string),
+			SecretKey:  secrets.Value["OKX_SECRET_KEY"].(string),
+			Passphrase: secrets.Value["OKX_PASSPHRASE"].(string),
+		}
+	case "coinbase":
+		return exchange.Credentials{
+			APIKey:    secrets.Value["COINBASE_API_KEY"].(string),
+			SecretKey: secrets.Value["COINBASE_SECRET_KEY"].(string),
+		}
+	default:
+		// BINANCE_SECRET_KEY is only required when no BINANCE_SIGNER_ENDPOINT
+		// is set: remote-signed users keep their secret key out of this
+		// process entirely.
+		secretKey, _ := secrets.Value["BINANCE_SECRET_KEY"].(string)
+		signerEndpoint, _ := secrets.Value["BINANCE_SIGNER_ENDPOINT"].(string)
+		return exchange.Credentials{
+			APIKey:         secrets.Value["BINANCE_API_KEY"].(string),
+			SecretKey:      secretKey,
+			SignerEndpoint: signerEndpoint,
+		}
+	}
 }
 
-type AccountInfo struct {
-	Balances []struct {
-		Asset string `json:"asset"`
-		Free  string `json:"free"`
-	} `json:"balances"`
+// resolveExchange picks the backend for this message: an explicit
+// "via <exchange>" override in the command wins, otherwise the user's
+// configured default (secrets.Value["EXCHANGE"]), otherwise Binance.
+func resolveExchange(secrets *secretstore.Secret, override string) (exchange.Exchange, string, error) {
+	name := override
+	if name == "" {
+		if configured, ok := secrets.Value["EXCHANGE"].(string); ok && configured != "" {
+			name = configured
+		} else {
+			name = defaultExchange
+		}
+	}
+	name = exchange.NormalizeName(name)
+
+	ex, err := exchange.New(name, exchangeCredentials(secrets, name))
+	if err != nil {
+		return nil, "", err
+	}
+	return ex, name, nil
+}
+
+// withExchangeOverride strips a trailing "via <exchange>" from the command's
+// tokens, e.g. "comprar 100 em btc via okx", returning the remaining tokens
+// and the requested exchange name (empty if none was given).
+func withExchangeOverride(parts []string) ([]string, string) {
+	if len(parts) >= 2 && parts[len(parts)-2] == "via" {
+		return parts[:len(parts)-2], parts[len(parts)-1]
+	}
+	return parts, ""
 }
 
 func verifyWebhook(c *gin.Context) {
@@ -199,6 +282,88 @@ func verifyWebhook(c *gin.Context) {
 	}
 }
 
+// verifyWhatsAppSignature validates the X-Hub-Signature-256 header Meta sends
+// on every callback, rejecting requests that weren't signed with
+// whatsappAppSecret. The raw body is read and restored so ShouldBindJSON can
+// still decode it downstream.
+func verifyWhatsAppSignature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawBody, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Payload inválido"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(rawBody))
+
+		signatureHeader := c.GetHeader("X-Hub-Signature-256")
+		expected := "sha256=" + hmacHex(whatsappAppSecret, rawBody)
+
+		if !hmac.Equal([]byte(signatureHeader), []byte(expected)) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Assinatura inválida"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func hmacHex(secret string, data []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// tokenBucket implements a simple per-key rate limiter: each call consumes one
+// token, and tokens refill continuously at refillPerSecond up to capacity.
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type tokenBucketLimiter struct {
+	mu              sync.Mutex
+	buckets         map[string]*tokenBucket
+	capacity        float64
+	refillPerSecond float64
+}
+
+func newRateLimiter(capacity float64, refillPerSecond float64) *tokenBucketLimiter {
+	return &tokenBucketLimiter{
+		buckets:         make(map[string]*tokenBucket),
+		capacity:        capacity,
+		refillPerSecond: refillPerSecond,
+	}
+}
+
+func (r *tokenBucketLimiter) allow(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: r.capacity, lastSeen: now}
+		r.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = minFloat(r.capacity, bucket.tokens+elapsed*r.refillPerSecond)
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 func handleWhatsAppWebhook(c *gin.Context) {
 	fmt.Println("recebendo hook whatsapp")
 	var req struct {
@@ -229,27 +394,233 @@ func handleWhatsAppWebhook(c *gin.Context) {
 	message := req.Entry[0].Changes[0].Value.Messages[0]
 	from := message.From
 	body := strings.ToLower(strings.TrimSpace(message.Text.Body))
+	c.Set("from", from)
+
+	if !rateLimiter.allow(from) {
+		replyWhatsApp(from, "Você atingiu o limite de mensagens. Tente novamente em instantes.")
+		c.JSON(http.StatusTooManyRequests, gin.H{"status": "Limite de requisições excedido"})
+		return
+	}
 
-	secrets, _ := accessSecretVersion(from)
-	binanceApiKey = secrets.Value["BINANCE_API_KEY"].(string)
-	binanceSecretKey = secrets.Value["BINANCE_SECRET_KEY"].(string)
+	ctx := context.Background()
+	secrets, _ := secretStore.Get(ctx, from)
 
 	switch {
-	case strings.Contains(body, "saldo") && strings.Contains(body, "reais"):
-		accountInfo, errAccountinfo := getAccountInfo()
-		if errAccountinfo != nil {
-			replyWhatsApp(from, "Erro ao consultar saldo.")
+	case strings.HasPrefix(body, "ordem"):
+		c.Set("intent", "ordem")
+		// ordem <valor> em <cripto> a <preço> [via <exchange>]
+		parts, override := withExchangeOverride(strings.Fields(body))
+		if len(parts) != 6 || parts[4] != "a" {
+			replyWhatsApp(from, "Formato inválido. Use: ordem <valor> em <cripto> a <preço> (exemplo: ordem 100 em BTC a 350000)")
+			return
+		}
+
+		amount, errAmount := parseValor(parts[1])
+		price, errPrice := parseValor(parts[5])
+		if errAmount != nil || amount <= 0 || errPrice != nil || price <= 0 {
+			replyWhatsApp(from, "O valor e o preço da ordem devem ser válidos e maiores que zero.")
+			return
+		}
+
+		crypto := strings.ToUpper(parts[3])
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return
+		}
+		if valid, _ := ex.ValidatePair(ctx, crypto+BRL); !valid {
+			replyWhatsApp(from, fmt.Sprintf("Desculpe, o par de moedas %s/BRL não é suportado.", crypto))
+			return
+		}
+
+		orderResponse, errOrder := ex.PlaceOrder(ctx, exchange.OrderRequest{
+			Symbol:   crypto + BRL,
+			Side:     "BUY",
+			Type:     exchange.TypeLimit,
+			Quantity: amount / price,
+			Price:    price,
+		})
+		if errOrder != nil {
+			replyWhatsApp(from, "Erro ao registrar a ordem limitada.")
+			return
+		}
+		replyWhatsApp(from, fmt.Sprintf("Ordem limitada registrada!\nMoeda: %s\nPreço: R$ %.2f\nID do Pedido: %v", crypto, price, orderResponse["orderId"]))
+
+	case strings.HasPrefix(body, "stop"):
+		c.Set("intent", "stop")
+		// stop <valor> em <cripto> se cair para <preço> [via <exchange>]
+		parts, override := withExchangeOverride(strings.Fields(body))
+		if len(parts) != 8 || parts[4] != "se" || parts[5] != "cair" || parts[6] != "para" {
+			replyWhatsApp(from, "Formato inválido. Use: stop <valor> em <cripto> se cair para <preço>")
+			return
+		}
+
+		amount, errAmount := parseValor(parts[1])
+		stopPrice, errPrice := parseValor(parts[7])
+		if errAmount != nil || amount <= 0 || errPrice != nil || stopPrice <= 0 {
+			replyWhatsApp(from, "O valor e o preço de stop devem ser válidos e maiores que zero.")
+			return
+		}
+
+		crypto := strings.ToUpper(parts[3])
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return
+		}
+		if valid, _ := ex.ValidatePair(ctx, crypto+BRL); !valid {
+			replyWhatsApp(from, fmt.Sprintf("Desculpe, o par de moedas %s/BRL não é suportado.", crypto))
+			return
+		}
+
+		orderResponse, errOrder := ex.PlaceOrder(ctx, exchange.OrderRequest{
+			Symbol:    crypto + BRL,
+			Side:      "SELL",
+			Type:      exchange.TypeStopLossLimit,
+			Quantity:  amount / stopPrice,
+			Price:     stopPrice,
+			StopPrice: stopPrice,
+		})
+		if errOrder != nil {
+			replyWhatsApp(from, "Erro ao registrar a ordem de stop-loss.")
+			return
+		}
+		replyWhatsApp(from, fmt.Sprintf("Ordem de stop-loss registrada!\nMoeda: %s\nPreço de disparo: R$ %.2f\nID do Pedido: %v", crypto, stopPrice, orderResponse["orderId"]))
+
+	case strings.HasPrefix(body, "agendar"):
+		c.Set("intent", "agendar")
+		// agendar <valor> em <cripto> toda <periodo> [via <exchange>]
+		parts, override := withExchangeOverride(strings.Fields(body))
+		if len(parts) != 6 || parts[4] != "toda" {
+			replyWhatsApp(from, "Formato inválido. Use: agendar <valor> em <cripto> toda <periodo> (dia, semana ou mes)")
+			return
+		}
+
+		amount, errAmount := parseValor(parts[1])
+		if errAmount != nil || amount <= 0 {
+			replyWhatsApp(from, "O valor para a compra agendada deve ser válido e maior que zero.")
+			return
+		}
+
+		crypto := strings.ToUpper(parts[3])
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return
+		}
+		if valid, _ := ex.ValidatePair(ctx, crypto+BRL); !valid {
+			replyWhatsApp(from, fmt.Sprintf("Desculpe, o par de moedas %s/BRL não é suportado.", crypto))
+			return
+		}
+
+		period, ok := periodoToDuration(parts[5])
+		if !ok {
+			replyWhatsApp(from, "Período inválido. Use: dia, semana ou mes.")
+			return
+		}
+
+		scheduler.schedule(&dcaJob{
+			from:     from,
+			symbol:   crypto + BRL,
+			amount:   amount,
+			period:   period,
+			override: override,
+		})
+		replyWhatsApp(from, fmt.Sprintf("Compra recorrente agendada!\nMoeda: %s\nValor: R$ %.2f\nFrequência: %s", crypto, amount, parts[5]))
+
+	case strings.Contains(body, "ordens abertas"):
+		c.Set("intent", "ordens_abertas")
+		// ordens abertas [<cripto>] [via <exchange>]
+		parts, override := withExchangeOverride(strings.Fields(body))
+		crypto := ""
+		if len(parts) > 2 {
+			crypto = strings.ToUpper(parts[len(parts)-1])
+		}
+
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return
+		}
+
+		symbol := ""
+		if crypto != "" {
+			symbol = crypto + BRL
+		}
+
+		orders, errOrders := ex.OpenOrders(ctx, symbol)
+		if errOrders != nil {
+			replyWhatsApp(from, "Erro ao consultar ordens abertas.")
+			return
+		}
+		if len(orders) == 0 {
+			replyWhatsApp(from, "Você não tem ordens abertas.")
 			return
 		}
 
+		var sb strings.Builder
+		sb.WriteString("Ordens abertas:\n")
+		for _, order := range orders {
+			sb.WriteString(fmt.Sprintf("ID %s: %s %s a R$ %.2f\n", order.OrderID, order.Side, order.Symbol, order.Price))
+		}
+		replyWhatsApp(from, sb.String())
+
+	case strings.HasPrefix(body, "cancelar") && len(strings.Fields(body)) > 1:
+		c.Set("intent", "cancelar_ordem")
+		// cancelar <orderId> em <cripto> [via <exchange>]
+		parts, override := withExchangeOverride(strings.Fields(body))
+		if len(parts) != 4 {
+			replyWhatsApp(from, "Formato inválido. Use: cancelar <id do pedido> em <cripto>")
+			return
+		}
+
+		crypto := strings.ToUpper(parts[3])
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return
+		}
+
+		if err := ex.CancelOrder(ctx, crypto+BRL, parts[1]); err != nil {
+			replyWhatsApp(from, "Erro ao cancelar a ordem.")
+			return
+		}
+		replyWhatsApp(from, fmt.Sprintf("Ordem %s cancelada com sucesso.", parts[1]))
+
+	default:
+		c.Set("intent", handleNaturalLanguage(ctx, from, body, secrets))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "Mensagem processada com sucesso"})
+}
+
+// handleNaturalLanguage is the fallback for messages that don't match one of
+// the structured command formats (ordem/stop/agendar/ordens abertas/cancelar
+// <id>). It routes free-form phrasing like "quanto tenho de saldo" or
+// "compra 100 reais de btc" through nlu.Parse instead of requiring users to
+// memorize an exact syntax.
+func handleNaturalLanguage(ctx context.Context, from, body string, secrets *secretstore.Secret) string {
+	intent := nlu.Parse(body)
+	_, override := withExchangeOverride(strings.Fields(body))
+
+	switch intent.Name {
+	case nlu.Balance:
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return intent.Name
+		}
+
+		accountInfo, errAccountInfo := ex.AccountInfo(ctx)
+		if errAccountInfo != nil {
+			replyWhatsApp(from, "Erro ao consultar saldo.")
+			return intent.Name
+		}
+
 		brlBalance := 0.0
 		for _, balance := range accountInfo.Balances {
 			if balance.Asset == BRL {
-				freeAmount, err := strconv.ParseFloat(balance.Free, 64)
-				if err != nil {
-					continue
-				}
-				brlBalance = freeAmount
+				brlBalance = balance.Free
 				break
 			}
 		}
@@ -260,52 +631,93 @@ func handleWhatsAppWebhook(c *gin.Context) {
 			replyWhatsApp(from, "Você não tem saldo disponível em reais.")
 		}
 
-	case strings.HasPrefix(body, "comprar"):
-		parts := strings.Fields(body)
-		if len(parts) != 4 {
-			replyWhatsApp(from, "Formato inválido. Use: comprar <valor> em <cripto> (exemplo: comprar 100R$ em BTC)")
-			return
+	case nlu.Buy:
+		amount, errAmount := parseValor(intent.Slots["amount"])
+		crypto := intent.Slots["asset"]
+		if errAmount != nil || amount <= 0 || crypto == "" {
+			replyWhatsApp(from, "Não entendi o valor ou a moeda da compra. Exemplo: comprar 100 reais de BTC")
+			return intent.Name
 		}
 
-		valueStr := strings.Replace(parts[1], "r$", "", -1)
-		valueStr = strings.Replace(valueStr, ",", ".", -1)
-		amount, err := strconv.ParseFloat(valueStr, 64)
-		if err != nil || amount <= 0 {
-			replyWhatsApp(from, "O valor para compra deve ser válido e maior que zero.")
-			return
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return intent.Name
 		}
 
-		crypto := strings.ToUpper(parts[3])
-
-		if !isTradingPairValid(crypto + BRL) {
+		if valid, _ := ex.ValidatePair(ctx, crypto+BRL); !valid {
 			replyWhatsApp(from, fmt.Sprintf("Desculpe, o par de moedas %s/BRL não é suportado.", crypto))
-			return
+			return intent.Name
 		}
 
-		accountInfo, errAccountInfo := getAccountInfo()
+		accountInfo, errAccountInfo := ex.AccountInfo(ctx)
 		if errAccountInfo != nil {
 			replyWhatsApp(from, "Erro ao validar saldo para compra.")
-			return
+			return intent.Name
 		}
 
 		if !hasSufficientBalance(accountInfo, BRL, amount) {
 			replyWhatsApp(from, "Saldo insuficiente para realizar a compra.")
-			return
+			return intent.Name
 		}
 
-		orderResponse := buyCrypto(crypto+BRL, amount)
-		if orderResponse == nil {
+		orderResponse, errOrder := ex.PlaceOrder(ctx, exchange.OrderRequest{
+			Symbol:        crypto + BRL,
+			Side:          "BUY",
+			Type:          exchange.TypeMarket,
+			QuoteOrderQty: amount,
+		})
+		if errOrder != nil {
 			replyWhatsApp(from, "Erro ao realizar a compra.")
-			return
+			return intent.Name
 		}
 
 		replyWhatsApp(from, fmt.Sprintf("Compra realizada com sucesso!\nMoeda: %s\nValor: R$ %.2f\nID do Pedido: %v", crypto, amount, orderResponse["orderId"]))
 
+	case nlu.Price:
+		crypto := intent.Slots["asset"]
+		if crypto == "" {
+			replyWhatsApp(from, "Não entendi qual moeda você quer cotar. Exemplo: preço do BTC")
+			return intent.Name
+		}
+
+		ex, _, errExchange := resolveExchange(secrets, override)
+		if errExchange != nil {
+			replyWhatsApp(from, "Exchange configurada é inválida.")
+			return intent.Name
+		}
+
+		price, errPrice := ex.Ticker(ctx, crypto+BRL)
+		if errPrice != nil {
+			replyWhatsApp(from, fmt.Sprintf("Desculpe, o par de moedas %s/BRL não é suportado.", crypto))
+			return intent.Name
+		}
+		replyWhatsApp(from, fmt.Sprintf("%s: R$ %.2f", crypto, price))
+
+	case nlu.Cancel:
+		if scheduler.cancel(from) {
+			replyWhatsApp(from, "Sua compra recorrente foi cancelada.")
+		} else {
+			replyWhatsApp(from, "Você não tem nenhuma compra recorrente agendada.")
+		}
+
+	case nlu.Help:
+		replyWhatsApp(from, "Comandos disponíveis:\n"+
+			"comprar <valor> em <cripto> - compra a mercado\n"+
+			"ordem <valor> em <cripto> a <preço> - ordem limitada\n"+
+			"stop <valor> em <cripto> se cair para <preço> - stop-loss\n"+
+			"agendar <valor> em <cripto> toda <periodo> - compra recorrente\n"+
+			"ordens abertas - lista suas ordens\n"+
+			"cancelar <id> em <cripto> - cancela uma ordem\n"+
+			"cancelar - cancela sua compra recorrente\n"+
+			"saldo em reais - consulta seu saldo\n"+
+			"preço do <cripto> - consulta a cotação")
+
 	default:
 		replyWhatsApp(from, "Desculpe, não reconheço este comando. Envie 'ajuda' para listar os comandos disponíveis.")
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "Mensagem processada com sucesso"})
+	return intent.Name
 }
 
 func replyWhatsApp(to string, message string) {
@@ -360,56 +772,118 @@ func replyWhatsApp(to string, message string) {
 	}
 }
 
-func isFiat(currency string) bool {
-	fiatCurrencies := map[string]bool{
-		"BRL": true,
-		"USD": true,
-		"EUR": true,
-	}
-
-	_, isFiat := fiatCurrencies[currency]
-	return isFiat
+// dcaJob represents a recurring buy scheduled by a user via the "agendar" command.
+type dcaJob struct {
+	from     string
+	symbol   string
+	amount   float64
+	period   time.Duration
+	override string
+	stop     chan struct{}
 }
 
-type Secret struct {
-	Name  string
-	Value map[string]interface{}
+// dcaScheduler keeps recurring DCA buys running in-process, keyed by phone
+// number. There's no persistence across restarts yet; jobs simply resume
+// being scheduled the next time the user issues "agendar".
+type dcaScheduler struct {
+	mu   sync.Mutex
+	jobs map[string]*dcaJob
 }
 
-func accessSecretVersion(name string) (*Secret, error) {
+func newDCAScheduler() *dcaScheduler {
+	return &dcaScheduler{jobs: make(map[string]*dcaJob)}
+}
 
-	// Create the client.
-	ctx := context.Background()
-	client, err := secretmanager.NewClient(ctx)
-	if err != nil {
-		log.Fatalf("failed to setup client: %v", err)
+func periodoToDuration(periodo string) (time.Duration, bool) {
+	switch periodo {
+	case "dia", "diariamente":
+		return 24 * time.Hour, true
+	case "semana", "semanalmente":
+		return 7 * 24 * time.Hour, true
+	case "mes", "mês", "mensalmente":
+		return 30 * 24 * time.Hour, true
+	default:
+		return 0, false
 	}
-	defer client.Close()
+}
 
-	accessRequest := &secretmanagerpb.AccessSecretVersionRequest{
-		Name: buildSecretPath(name),
-	}
+func (s *dcaScheduler) schedule(job *dcaJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Call the API.
-	result, err := client.AccessSecretVersion(ctx, accessRequest)
-	if err != nil {
-		return nil, err
+	if existing, ok := s.jobs[job.from]; ok {
+		close(existing.stop)
 	}
 
-	var secretData map[string]interface{}
-	if err := json.Unmarshal(result.Payload.Data, &secretData); err != nil {
-		return nil, err
-	}
+	job.stop = make(chan struct{})
+	s.jobs[job.from] = job
+
+	go func() {
+		ticker := time.NewTicker(job.period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// Re-fetch credentials on every tick rather than caching them
+				// at schedule time, so a rotated key or a changed EXCHANGE
+				// secret takes effect on the next run instead of a standing
+				// job silently signing with a stale credential forever.
+				secrets, err := secretStore.Get(context.Background(), job.from)
+				if err != nil {
+					replyWhatsApp(job.from, fmt.Sprintf("Falha ao executar compra agendada de %s.", job.symbol))
+					continue
+				}
+
+				ex, _, err := resolveExchange(secrets, job.override)
+				if err != nil {
+					replyWhatsApp(job.from, fmt.Sprintf("Falha ao executar compra agendada de %s.", job.symbol))
+					continue
+				}
+
+				if _, err := ex.PlaceOrder(context.Background(), exchange.OrderRequest{
+					Symbol:        job.symbol,
+					Side:          "BUY",
+					Type:          exchange.TypeMarket,
+					QuoteOrderQty: job.amount,
+				}); err != nil {
+					replyWhatsApp(job.from, fmt.Sprintf("Falha ao executar compra agendada de %s.", job.symbol))
+					continue
+				}
+				replyWhatsApp(job.from, fmt.Sprintf("Compra agendada executada!\nMoeda: %s\nValor: R$ %.2f", job.symbol, job.amount))
+			case <-job.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *dcaScheduler) cancel(from string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	secret := &Secret{
-		Name:  name,
-		Value: secretData,
+	job, ok := s.jobs[from]
+	if !ok {
+		return false
 	}
+	close(job.stop)
+	delete(s.jobs, from)
+	return true
+}
 
-	return secret, nil
+func parseValor(raw string) (float64, error) {
+	valueStr := strings.Replace(raw, "r$", "", -1)
+	valueStr = strings.Replace(valueStr, ",", ".", -1)
+	return strconv.ParseFloat(valueStr, 64)
 }
 
-func buildSecretPath(secretName string) string {
-	projectID := "425135792660"
-	return fmt.Sprintf("projects/%s/secrets/%s/versions/latest", projectID, secretName)
+func isFiat(currency string) bool {
+	fiatCurrencies := map[string]bool{
+		"BRL": true,
+		"USD": true,
+		"EUR": true,
+	}
+
+	_, isFiat := fiatCurrencies[currency]
+	return isFiat
 }
+
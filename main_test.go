@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiterAllowsUpToCapacity(t *testing.T) {
+	limiter := newRateLimiter(3, 1)
+
+	for i := 0; i < 3; i++ {
+		if !limiter.allow("user-1") {
+			t.Fatalf("request %d: expected allow, got denied", i+1)
+		}
+	}
+	if limiter.allow("user-1") {
+		t.Fatal("request 4: expected denied, got allowed")
+	}
+}
+
+func TestTokenBucketLimiterTracksKeysIndependently(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+
+	if !limiter.allow("user-1") {
+		t.Fatal("user-1's first request should be allowed")
+	}
+	if !limiter.allow("user-2") {
+		t.Fatal("user-2's bucket is independent of user-1's and should still be full")
+	}
+}
+
+func TestTokenBucketLimiterRefillsOverTime(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+
+	if !limiter.allow("user-1") {
+		t.Fatal("first request should be allowed")
+	}
+	if limiter.allow("user-1") {
+		t.Fatal("second immediate request should be denied")
+	}
+
+	// Backdate lastSeen instead of sleeping, so the test stays fast.
+	limiter.buckets["user-1"].lastSeen = time.Now().Add(-2 * time.Second)
+	if !limiter.allow("user-1") {
+		t.Fatal("request after refill window should be allowed")
+	}
+}
+
+func TestHmacHexIsDeterministicAndKeyed(t *testing.T) {
+	sig1 := hmacHex("secret", []byte("payload"))
+	sig2 := hmacHex("secret", []byte("payload"))
+	if sig1 != sig2 {
+		t.Fatalf("hmacHex(%q, %q) is not deterministic: %q != %q", "secret", "payload", sig1, sig2)
+	}
+
+	if sig3 := hmacHex("other-secret", []byte("payload")); sig3 == sig1 {
+		t.Fatal("hmacHex should differ when the secret differs")
+	}
+}